@@ -0,0 +1,182 @@
+/*
+	Author: Christian (Sapphire) Godard
+	Date: 2/18/2025
+	File: bulk.go
+	Description: Batch ingest and paged query endpoints for receipts, modeled as Command
+	             structs so request parsing is reusable across handlers
+*/
+
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxUniqueIDAttempts bounds how many UUIDs uniqueReceiptID will try before giving up
+const maxUniqueIDAttempts = 5
+
+// BulkReceiptsCommand is the request body for POST /receipts/bulk
+type BulkReceiptsCommand struct {
+	Receipts []Receipt `json:"receipts"`
+}
+
+// LoadDataFromRequest binds the request body into cmd
+func (cmd *BulkReceiptsCommand) LoadDataFromRequest(c *gin.Context) error {
+	return c.ShouldBindJSON(cmd)
+}
+
+// BulkReceiptResult is the per-item outcome returned by POST /receipts/bulk
+type BulkReceiptResult struct {
+	ID     string `json:"id,omitempty"`
+	Points int    `json:"points,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// postReceiptsBulk accepts an array of receipts and processes each independently, so one
+// invalid receipt doesn't fail the whole batch
+func postReceiptsBulk(c *gin.Context) {
+	var cmd BulkReceiptsCommand
+
+	if err := cmd.LoadDataFromRequest(c); err != nil {
+		RenderError(c, http.StatusBadRequest, CodeValidationFailure, "Malformed bulk request: "+err.Error())
+		return
+	}
+
+	results := make([]BulkReceiptResult, 0, len(cmd.Receipts))
+
+	for _, receipt := range cmd.Receipts {
+		if fieldErrs := ValidateReceipt(receipt); len(fieldErrs) > 0 {
+			results = append(results, BulkReceiptResult{Error: fieldErrs[0].Field + ": " + fieldErrs[0].Reason})
+			continue
+		}
+
+		id, err := uniqueReceiptID()
+
+		if err != nil {
+			results = append(results, BulkReceiptResult{Error: err.Error()})
+			continue
+		}
+
+		points := receiptPoints(receipt)
+
+		if err := store.Put(id, receipt, points); err != nil {
+			results = append(results, BulkReceiptResult{Error: err.Error()})
+			continue
+		}
+
+		results = append(results, BulkReceiptResult{ID: id, Points: points})
+	}
+
+	RenderSuccess(c, http.StatusOK, results)
+}
+
+// QueryCommand is the request body for POST /receipts/query
+type QueryCommand struct {
+	Retailer  string  `json:"retailer"`
+	DateFrom  string  `json:"dateFrom"`
+	DateTo    string  `json:"dateTo"`
+	MinTotal  float64 `json:"minTotal"`
+	MaxTotal  float64 `json:"maxTotal"`
+	MinPoints int     `json:"minPoints"`
+	Page      int     `json:"page"`
+	PageSize  int     `json:"pageSize"`
+	SortOrder string  `json:"sortOrder"`
+}
+
+// LoadDataFromRequest binds the request body into cmd
+func (cmd *QueryCommand) LoadDataFromRequest(c *gin.Context) error {
+	return c.ShouldBindJSON(cmd)
+}
+
+// toFilter converts cmd into the ReceiptFilter the store understands
+func (cmd *QueryCommand) toFilter() ReceiptFilter {
+	return ReceiptFilter{
+		RetailerContains: cmd.Retailer,
+		PurchasedAfter:   cmd.DateFrom,
+		PurchasedBefore:  cmd.DateTo,
+		MinTotal:         cmd.MinTotal,
+		MaxTotal:         cmd.MaxTotal,
+		MinPoints:        cmd.MinPoints,
+		Page:             cmd.Page,
+		PageSize:         cmd.PageSize,
+		SortOrder:        cmd.SortOrder,
+	}
+}
+
+// postReceiptsQuery returns a paged, filtered listing of receipts
+func postReceiptsQuery(c *gin.Context) {
+	var cmd QueryCommand
+
+	if err := cmd.LoadDataFromRequest(c); err != nil {
+		RenderError(c, http.StatusBadRequest, CodeValidationFailure, "Malformed query request: "+err.Error())
+		return
+	}
+
+	results, err := store.List(cmd.toFilter())
+
+	if err != nil {
+		RenderError(c, http.StatusInternalServerError, CodePointsComputationFailure, err.Error())
+		return
+	}
+
+	RenderSuccess(c, http.StatusOK, results)
+}
+
+// BulkStatusCommand is the request body for POST /receipts/bulk-status
+type BulkStatusCommand struct {
+	IDs    []string `json:"ids"`
+	Status string   `json:"status"`
+}
+
+// LoadDataFromRequest binds the request body into cmd
+func (cmd *BulkStatusCommand) LoadDataFromRequest(c *gin.Context) error {
+	return c.ShouldBindJSON(cmd)
+}
+
+// postReceiptsBulkStatus attaches a status (e.g. "processed", "flagged") to a set of receipts
+func postReceiptsBulkStatus(c *gin.Context) {
+	var cmd BulkStatusCommand
+
+	if err := cmd.LoadDataFromRequest(c); err != nil {
+		RenderError(c, http.StatusBadRequest, CodeValidationFailure, "Malformed bulk-status request: "+err.Error())
+		return
+	}
+
+	results := make([]BulkReceiptResult, 0, len(cmd.IDs))
+
+	for _, id := range cmd.IDs {
+		if err := store.SetStatus(id, cmd.Status); err != nil {
+			results = append(results, BulkReceiptResult{ID: id, Error: err.Error()})
+			continue
+		}
+
+		results = append(results, BulkReceiptResult{ID: id})
+	}
+
+	RenderSuccess(c, http.StatusOK, results)
+}
+
+// uniqueReceiptID returns a fresh UUID confirmed absent from store. A transient store error
+// (dropped connection, timeout, ...) is propagated rather than treated as "ID is free", since
+// that could otherwise let Put silently overwrite an unrelated existing receipt
+func uniqueReceiptID() (string, error) {
+	for attempt := 0; attempt < maxUniqueIDAttempts; attempt++ {
+		id := uuid.New().String()
+
+		_, err := store.Get(id)
+
+		if errors.Is(err, ErrReceiptNotFound) {
+			return id, nil
+		}
+
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return "", errors.New("couldn't generate a unique receipt id")
+}
@@ -0,0 +1,99 @@
+/*
+	Author: Christian (Sapphire) Godard
+	Date: 2/10/2025
+	File: filter.go
+	Description: Shared helpers for applying a ReceiptFilter and paging results, used by stores
+	             that don't push filtering down into a query language
+*/
+
+package main
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// matchesFilter reports whether r satisfies every criterion set on filter
+func matchesFilter(r StoredReceipt, filter ReceiptFilter) bool {
+	if filter.RetailerContains != "" && !strings.Contains(strings.ToLower(r.Retailer), strings.ToLower(filter.RetailerContains)) {
+		return false
+	}
+
+	if filter.PurchasedAfter != "" && r.PurchaseDate < filter.PurchasedAfter {
+		return false
+	}
+
+	if filter.PurchasedBefore != "" && r.PurchaseDate > filter.PurchasedBefore {
+		return false
+	}
+
+	total := parseTotal(r.Total)
+
+	if filter.MinTotal != 0 && total < filter.MinTotal {
+		return false
+	}
+
+	if filter.MaxTotal != 0 && total > filter.MaxTotal {
+		return false
+	}
+
+	if r.Points < filter.MinPoints {
+		return false
+	}
+
+	return true
+}
+
+// parseTotal converts a receipt's Total field to a float, treating unparsable values as 0
+func parseTotal(total string) float64 {
+	value, err := strconv.ParseFloat(total, 64)
+
+	if err != nil {
+		return 0
+	}
+
+	return value
+}
+
+// sortReceipts orders results by purchase date/time in place. order is "asc" or "desc"
+// (the default, matching the newest-first order stores already return)
+func sortReceipts(results []StoredReceipt, order string) {
+	ascending := order == "asc"
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a := results[i].PurchaseDate + results[i].PurchaseTime
+		b := results[j].PurchaseDate + results[j].PurchaseTime
+
+		if ascending {
+			return a < b
+		}
+
+		return a > b
+	})
+}
+
+// paginate slices matches down to the requested page, defaulting to page 1 of 20
+func paginate(matches []StoredReceipt, page int, pageSize int) []StoredReceipt {
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	start := (page - 1) * pageSize
+
+	if start >= len(matches) {
+		return []StoredReceipt{}
+	}
+
+	end := start + pageSize
+
+	if end > len(matches) {
+		end = len(matches)
+	}
+
+	return matches[start:end]
+}
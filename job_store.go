@@ -0,0 +1,216 @@
+/*
+	Author: Christian (Sapphire) Godard
+	Date: 2/26/2025
+	File: job_store.go
+	Description: Persistence for async receipt-processing jobs, mirroring the ReceiptStore
+	             pattern so job state survives restarts and in-flight work can resume
+*/
+
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"sync"
+)
+
+// JobStatus is the lifecycle state of an async processing job
+type JobStatus string
+
+const (
+	JobPending    JobStatus = "pending"
+	JobProcessing JobStatus = "processing"
+	JobCompleted  JobStatus = "completed"
+	JobFailed     JobStatus = "failed"
+)
+
+// ErrJobNotFound is returned by a JobStore when a lookup doesn't match any job
+var ErrJobNotFound = errors.New("job not found")
+
+// CallbackDeliveryStatus is the outcome of delivering a job's webhook callback, tracked
+// separately from JobStatus so a delivery failure doesn't read as a processing failure
+type CallbackDeliveryStatus string
+
+const (
+	CallbackDelivered CallbackDeliveryStatus = "delivered"
+	CallbackFailed    CallbackDeliveryStatus = "failed"
+)
+
+// Job tracks the async processing of one receipt
+type Job struct {
+	ID             string                 `json:"id"`
+	ReceiptID      string                 `json:"receiptId"`
+	Status         JobStatus              `json:"status"`
+	Points         int                    `json:"points,omitempty"`
+	CallbackURL    string                 `json:"callbackUrl,omitempty"`
+	Attempts       int                    `json:"attempts,omitempty"`
+	Error          string                 `json:"error,omitempty"`
+	CallbackStatus CallbackDeliveryStatus `json:"callbackStatus,omitempty"`
+	CallbackError  string                 `json:"callbackError,omitempty"`
+}
+
+// JobStore is implemented by every persistence driver async jobs can be tracked in
+type JobStore interface {
+	Put(job Job) error
+	Get(id string) (Job, error)
+	// PendingJobs returns every job still pending or processing, used to resume work after a restart
+	PendingJobs() ([]Job, error)
+	Close() error
+}
+
+// MemoryJobStore is a JobStore backed by a plain map. Job state is lost on restart
+type MemoryJobStore struct {
+	mu   sync.RWMutex
+	jobs map[string]Job
+}
+
+// NewMemoryJobStore returns an empty MemoryJobStore
+func NewMemoryJobStore() *MemoryJobStore {
+	return &MemoryJobStore{jobs: make(map[string]Job)}
+}
+
+func (s *MemoryJobStore) Put(job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs[job.ID] = job
+	return nil
+}
+
+func (s *MemoryJobStore) Get(id string) (Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if job, exists := s.jobs[id]; exists {
+		return job, nil
+	}
+
+	return Job{}, ErrJobNotFound
+}
+
+func (s *MemoryJobStore) PendingJobs() ([]Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pending := make([]Job, 0)
+
+	for _, job := range s.jobs {
+		if job.Status == JobPending || job.Status == JobProcessing {
+			pending = append(pending, job)
+		}
+	}
+
+	return pending, nil
+}
+
+func (s *MemoryJobStore) Close() error {
+	return nil
+}
+
+const jobsSchema = `
+CREATE TABLE IF NOT EXISTS jobs (
+	id              TEXT PRIMARY KEY,
+	receipt_id      TEXT NOT NULL,
+	status          TEXT NOT NULL,
+	points          INTEGER NOT NULL DEFAULT 0,
+	callback_url    TEXT NOT NULL DEFAULT '',
+	attempts        INTEGER NOT NULL DEFAULT 0,
+	error           TEXT NOT NULL DEFAULT '',
+	callback_status TEXT NOT NULL DEFAULT '',
+	callback_error  TEXT NOT NULL DEFAULT ''
+);`
+
+// SQLJobStore is a JobStore backed by a database/sql connection pool
+type SQLJobStore struct {
+	db         *sql.DB
+	driverName string
+}
+
+// NewSQLJobStore opens driverName/dataSourceName, validates the connection, and migrates the
+// jobs table
+func NewSQLJobStore(driverName string, dataSourceName string) (*SQLJobStore, error) {
+	db, err := sql.Open(driverName, dataSourceName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if driverName == "sqlite3" {
+		// SQLite serializes writers per connection; a pool would hand out separate
+		// (and, for ":memory:" DSNs, entirely distinct) connections otherwise
+		db.SetMaxOpenConns(1)
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, err
+	}
+
+	if _, err := db.Exec(jobsSchema); err != nil {
+		return nil, err
+	}
+
+	return &SQLJobStore{db: db, driverName: driverName}, nil
+}
+
+// rebind rewrites query's "?" placeholders for s.driverName
+func (s *SQLJobStore) rebind(query string) string {
+	return rebindPlaceholders(s.driverName, query)
+}
+
+func (s *SQLJobStore) Put(job Job) error {
+	_, err := s.db.Exec(
+		s.rebind(`INSERT INTO jobs (id, receipt_id, status, points, callback_url, attempts, error, callback_status, callback_error)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT (id) DO UPDATE SET status = excluded.status, points = excluded.points,
+			attempts = excluded.attempts, error = excluded.error, callback_status = excluded.callback_status,
+			callback_error = excluded.callback_error`),
+		job.ID, job.ReceiptID, job.Status, job.Points, job.CallbackURL, job.Attempts, job.Error,
+		job.CallbackStatus, job.CallbackError,
+	)
+
+	return err
+}
+
+func (s *SQLJobStore) Get(id string) (Job, error) {
+	var job Job
+
+	err := s.db.QueryRow(
+		s.rebind(`SELECT id, receipt_id, status, points, callback_url, attempts, error, callback_status, callback_error FROM jobs WHERE id = ?`), id,
+	).Scan(&job.ID, &job.ReceiptID, &job.Status, &job.Points, &job.CallbackURL, &job.Attempts, &job.Error, &job.CallbackStatus, &job.CallbackError)
+
+	if err == sql.ErrNoRows {
+		return Job{}, ErrJobNotFound
+	}
+
+	return job, err
+}
+
+func (s *SQLJobStore) PendingJobs() ([]Job, error) {
+	rows, err := s.db.Query(
+		s.rebind(`SELECT id, receipt_id, status, points, callback_url, attempts, error, callback_status, callback_error FROM jobs WHERE status IN (?, ?)`),
+		JobPending, JobProcessing,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := make([]Job, 0)
+
+	for rows.Next() {
+		var job Job
+
+		if err := rows.Scan(&job.ID, &job.ReceiptID, &job.Status, &job.Points, &job.CallbackURL, &job.Attempts, &job.Error, &job.CallbackStatus, &job.CallbackError); err != nil {
+			return nil, err
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	return jobs, rows.Err()
+}
+
+func (s *SQLJobStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,36 @@
+/*
+	Author: Christian (Sapphire) Godard
+	Date: 3/3/2025
+	File: logging.go
+	Description: Structured JSON logging so operators can correlate requests across log lines
+	             by request ID instead of grepping plain-text messages
+*/
+
+package main
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// logger is the process-wide structured logger, set up by newLogger in main
+var logger *zap.Logger
+
+// newLogger builds a production zap logger that emits JSON lines
+func newLogger() (*zap.Logger, error) {
+	return zap.NewProduction()
+}
+
+const loggerContextKey = "logger"
+
+// loggerFromContext returns the request-scoped logger attached by RequestContextMiddleware,
+// falling back to the process-wide logger if none was attached
+func loggerFromContext(c *gin.Context) *zap.Logger {
+	if value, exists := c.Get(loggerContextKey); exists {
+		if requestLogger, ok := value.(*zap.Logger); ok {
+			return requestLogger
+		}
+	}
+
+	return logger
+}
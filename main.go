@@ -8,14 +8,15 @@
 package main
 
 import (
-	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
-	"log"
-	"math"
 	"net/http"
-	"strconv"
+	"os"
 	"strings"
-	"time"
+
+	"github.com/gin-gonic/gin"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
 )
 
 // Item holds the description and price of items that are associated with a receipt
@@ -33,73 +34,79 @@ type Receipt struct {
 	Items        []Item `json:"items"`
 }
 
-var receipts = make(map[string]Receipt)
+// store is the ReceiptStore the API is running against, chosen at startup by newStoreFromEnv
+var store ReceiptStore
 
-/*
-Given a specific receipt it will score it based on a multitude of criteria relating to name of the retailer, total
-amount on purchase, how many items were purchased, length of item descriptions, purchase date, and purchase time
-*/
-func receiptPoints(receipt Receipt) (points int) {
-	points = 0
+// newStoreFromEnv picks a ReceiptStore driver based on DATABASE_URL. With no DATABASE_URL set,
+// receipts are kept in memory only, matching the API's original behavior
+func newStoreFromEnv() (ReceiptStore, error) {
+	databaseURL := os.Getenv("DATABASE_URL")
 
-	// Alphanumeric retailer character check
-	for _, char := range receipt.Retailer {
-		if (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || (char >= '0' && char <= '9') {
-			points++
-		}
+	if databaseURL == "" {
+		return NewMemoryStore(), nil
 	}
 
-	// Receipt total checks
-	receiptTotal, _ := strconv.ParseFloat(receipt.Total, 64)
+	driverName := "postgres"
 
-	if math.Mod(receiptTotal, 1.00) == 0 {
-		points += 50
+	if strings.HasPrefix(databaseURL, "sqlite://") {
+		driverName = "sqlite3"
+		databaseURL = strings.TrimPrefix(databaseURL, "sqlite://")
 	}
 
-	if math.Mod(receiptTotal, 0.25) == 0 {
-		points += 25
-	}
+	return NewSQLStore(driverName, databaseURL)
+}
 
-	// Receipt items check
-	points += 5 * (len(receipt.Items) / 2)
+// ruleRegistry holds per-retailer scoring overrides, loaded at startup by newRuleRegistryFromEnv
+var ruleRegistry = NewRuleRegistry()
 
-	// Item description check
-	for _, currItem := range receipt.Items {
-		if len(strings.Trim(currItem.ShortDescription, " "))%3 == 0 {
-			currPrice, _ := strconv.ParseFloat(currItem.Price, 64)
-			points += int(math.Ceil(currPrice * 0.2))
-		}
+// newRuleRegistryFromEnv loads per-retailer rule overrides from RULES_CONFIG_PATH, if set.
+// With no path set, every retailer scores against defaultScorer
+func newRuleRegistryFromEnv() (*RuleRegistry, error) {
+	path := os.Getenv("RULES_CONFIG_PATH")
+
+	if path == "" {
+		return NewRuleRegistry(), nil
 	}
 
-	// Date check
-	receiptDate, err := time.Parse("2006-01-02", receipt.PurchaseDate)
+	return LoadRuleRegistry(path)
+}
 
-	if err != nil {
-		log.Println("Couldn't parse receipt date of " + receipt.PurchaseDate)
-		return 0
-	}
+// receiptPoints scores receipt using whichever Scorer applies to its retailer, falling back
+// to defaultScorer when no override is registered
+func receiptPoints(receipt Receipt) int {
+	total, breakdown := ruleRegistry.ScorerFor(receipt.Retailer).Score(receipt)
+	recordScoring(total, breakdown)
+	return total
+}
 
-	if receiptDate.Day()%2 != 0 {
-		points += 6
-	}
+// jobStore tracks async processing jobs, chosen at startup by newJobStoreFromEnv
+var jobStore JobStore
 
-	// Time check
-	receiptTime, err := time.Parse("15:04", receipt.PurchaseTime)
+// newJobStoreFromEnv picks a JobStore driver using the same DATABASE_URL as newStoreFromEnv,
+// so job state survives restarts whenever receipts do
+func newJobStoreFromEnv() (JobStore, error) {
+	databaseURL := os.Getenv("DATABASE_URL")
 
-	if err != nil {
-		log.Println("Couldn't parse receipt time of " + receipt.PurchaseTime)
-		return 0
+	if databaseURL == "" {
+		return NewMemoryJobStore(), nil
 	}
 
-	if receiptTime.Hour() >= 14 && receiptTime.Hour() < 16 {
-		if receiptTime.Hour() == 14 && receiptTime.Minute() > 0 {
-			points += 10
-		} else {
-			points += 10
-		}
+	driverName := "postgres"
+
+	if strings.HasPrefix(databaseURL, "sqlite://") {
+		driverName = "sqlite3"
+		databaseURL = strings.TrimPrefix(databaseURL, "sqlite://")
 	}
 
-	return points
+	return NewSQLJobStore(driverName, databaseURL)
+}
+
+// ProcessReceiptRequest is the body accepted by POST /receipts/process: a Receipt plus
+// optional async-processing options
+type ProcessReceiptRequest struct {
+	Receipt
+	Async       bool   `json:"async"`
+	CallbackURL string `json:"callbackUrl"`
 }
 
 /*
@@ -109,55 +116,149 @@ return that.
 func getReceiptPoints(c *gin.Context) {
 	receiptId := c.Param("id")
 
-	if currReceipt, exists := receipts[receiptId]; exists {
-		c.IndentedJSON(http.StatusOK, gin.H{"points": receiptPoints(currReceipt)})
+	stored, err := store.Get(receiptId)
+
+	if err != nil {
+		loggerFromContext(c).Info("receipt not found", zap.String("receiptId", receiptId))
+		RenderError(c, http.StatusNotFound, CodeReceiptNotFound, "Receipt not found")
 		return
 	}
 
-	c.IndentedJSON(http.StatusNotFound, gin.H{"message": "Receipt not found"})
+	if c.Query("explain") != "true" {
+		RenderSuccess(c, http.StatusOK, gin.H{"points": stored.Points})
+		return
+	}
+
+	total, breakdown := ruleRegistry.ScorerFor(stored.Retailer).Score(stored.Receipt)
+	RenderSuccess(c, http.StatusOK, gin.H{"points": total, "breakdown": breakdown})
 }
 
 /*
-Creates receipt and adds it to our receipt collection if it is valid
+Creates receipt and adds it to our receipt collection if it is valid. When the request sets
+async: true, points are computed by a background worker instead and the caller polls (or is
+called back on) for the result
 */
 func postReceipt(c *gin.Context) {
-	var newReceipt Receipt
+	var req ProcessReceiptRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RenderError(c, http.StatusBadRequest, CodeValidationFailure, "Malformed receipt body: "+err.Error())
+		return
+	}
 
-	if err := c.BindJSON(&newReceipt); err != nil {
-		c.IndentedJSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+	if fieldErrs := ValidateReceipt(req.Receipt); len(fieldErrs) > 0 {
+		c.IndentedJSON(http.StatusBadRequest, APIResponse{Code: CodeValidationFailure, ErrMsg: "Receipt failed validation", Data: fieldErrs})
 		return
 	}
 
-	receiptGuid := uuid.New()
+	id, err := uniqueReceiptID()
 
-	for {
-		if _, exists := receipts[receiptGuid.String()]; exists {
-			receiptGuid = uuid.New()
-		} else {
-			break
+	if err != nil {
+		RenderError(c, http.StatusInternalServerError, CodePointsComputationFailure, err.Error())
+		return
+	}
+
+	if !req.Async {
+		points := receiptPoints(req.Receipt)
+
+		if err := store.Put(id, req.Receipt, points); err != nil {
+			RenderError(c, http.StatusInternalServerError, CodePointsComputationFailure, err.Error())
+			return
 		}
+
+		loggerFromContext(c).Info("receipt processed",
+			zap.String("receiptId", id), zap.String("retailer", req.Retailer), zap.Int("points", points))
+		RenderSuccess(c, http.StatusCreated, gin.H{"id": id})
+		return
 	}
 
-	receipts[receiptGuid.String()] = newReceipt
-	c.IndentedJSON(http.StatusCreated, gin.H{"id": receiptGuid.String()})
+	if err := store.Put(id, req.Receipt, 0); err != nil {
+		RenderError(c, http.StatusInternalServerError, CodePointsComputationFailure, err.Error())
+		return
+	}
+
+	job := Job{ID: id, ReceiptID: id, Status: JobPending, CallbackURL: req.CallbackURL}
+
+	if err := jobStore.Put(job); err != nil {
+		RenderError(c, http.StatusInternalServerError, CodePointsComputationFailure, err.Error())
+		return
+	}
+
+	jobQueue <- job.ID
+	RenderSuccess(c, http.StatusAccepted, gin.H{"id": id, "status": JobPending})
+}
+
+/*
+Reports the status of an async processing job (and its points once completed)
+*/
+func getReceiptStatus(c *gin.Context) {
+	job, err := jobStore.Get(c.Param("id"))
+
+	if err != nil {
+		RenderError(c, http.StatusNotFound, CodeReceiptNotFound, "Job not found")
+		return
+	}
+
+	RenderSuccess(c, http.StatusOK, job)
 }
 
 /*
 Create receipt API and run it on localhost:8080. Check if error occurred when starting it
 */
 func main() {
+	var err error
+
+	logger, err = newLogger()
+
+	if err != nil {
+		panic("Couldn't set up logger: " + err.Error())
+	}
+	defer logger.Sync()
+
+	store, err = newStoreFromEnv()
+
+	if err != nil {
+		logger.Fatal("Couldn't set up receipt store", zap.Error(err))
+	}
+	defer store.Close()
+
+	ruleRegistry, err = newRuleRegistryFromEnv()
+
+	if err != nil {
+		logger.Fatal("Couldn't load retailer rule overrides", zap.Error(err))
+	}
+
+	jobStore, err = newJobStoreFromEnv()
+
+	if err != nil {
+		logger.Fatal("Couldn't set up job store", zap.Error(err))
+	}
+	defer jobStore.Close()
+
+	startWorkerPool(4)
+
+	if err := enqueuePendingJobs(); err != nil {
+		logger.Fatal("Couldn't resume pending jobs", zap.Error(err))
+	}
+
 	router := gin.Default()
+	router.Use(RequestContextMiddleware())
+
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
 	api := router.Group("/receipts")
 	{
 		api.GET("/:id/points", getReceiptPoints)
+		api.GET("/:id/status", getReceiptStatus)
 		api.POST("/process", postReceipt)
+		api.POST("/bulk", postReceiptsBulk)
+		api.POST("/query", postReceiptsQuery)
+		api.POST("/bulk-status", postReceiptsBulkStatus)
 	}
 
-	err := router.Run("localhost:8080")
+	err = router.Run("localhost:8080")
 
 	if err != nil {
-		log.Fatal("Something BAD HAPPENED" + err.Error())
-		return
+		logger.Fatal("Something BAD HAPPENED", zap.Error(err))
 	}
 }
@@ -0,0 +1,77 @@
+/*
+	Author: Christian (Sapphire) Godard
+	Date: 2/10/2025
+	File: memory_store.go
+	Description: Process-local ReceiptStore backed by a plain map, the original storage
+	             behavior of the API before persistent drivers existed
+*/
+
+package main
+
+import "sync"
+
+// MemoryStore is a ReceiptStore that keeps every receipt in memory. Contents are lost on restart
+type MemoryStore struct {
+	mu       sync.RWMutex
+	receipts map[string]StoredReceipt
+}
+
+// NewMemoryStore returns an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{receipts: make(map[string]StoredReceipt)}
+}
+
+func (s *MemoryStore) Put(id string, r Receipt, points int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.receipts[id] = StoredReceipt{ID: id, Points: points, Receipt: r}
+	return nil
+}
+
+func (s *MemoryStore) Get(id string) (StoredReceipt, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if r, exists := s.receipts[id]; exists {
+		return r, nil
+	}
+
+	return StoredReceipt{}, ErrReceiptNotFound
+}
+
+func (s *MemoryStore) List(filter ReceiptFilter) ([]StoredReceipt, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matches := make([]StoredReceipt, 0, len(s.receipts))
+
+	for _, r := range s.receipts {
+		if matchesFilter(r, filter) {
+			matches = append(matches, r)
+		}
+	}
+
+	sortReceipts(matches, filter.SortOrder)
+
+	return paginate(matches, filter.Page, filter.PageSize), nil
+}
+
+func (s *MemoryStore) SetStatus(id string, status string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, exists := s.receipts[id]
+
+	if !exists {
+		return ErrReceiptNotFound
+	}
+
+	r.Status = status
+	s.receipts[id] = r
+	return nil
+}
+
+func (s *MemoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,68 @@
+/*
+	Author: Christian (Sapphire) Godard
+	Date: 3/3/2025
+	File: metrics.go
+	Description: Prometheus metrics for the receipt API, exposed on /metrics
+*/
+
+package main
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	receiptsProcessedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "receipts_processed_total",
+		Help: "Total number of receipts that have been scored",
+	})
+
+	pointsDistribution = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "receipt_points_distribution",
+		Help:    "Distribution of computed receipt points",
+		Buckets: prometheus.LinearBuckets(0, 25, 12),
+	})
+
+	ruleContributionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "receipt_rule_contributions_total",
+		Help: "Number of times each scoring rule contributed points to a receipt",
+	}, []string{"rule"})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by route",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	httpErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_errors_total",
+		Help: "HTTP responses by status class (4xx, 5xx)",
+	}, []string{"route", "status_class"})
+)
+
+// recordScoring updates the receipts-processed, points-distribution, and per-rule metrics for
+// one scored receipt
+func recordScoring(total int, breakdown []RuleResult) {
+	receiptsProcessedTotal.Inc()
+	pointsDistribution.Observe(float64(total))
+
+	for _, result := range breakdown {
+		ruleContributionsTotal.WithLabelValues(result.Rule).Inc()
+	}
+}
+
+// recordHTTPRequest updates per-route latency and error-count metrics for one request
+func recordHTTPRequest(method string, route string, status int, seconds float64) {
+	httpRequestDuration.WithLabelValues(method, route).Observe(seconds)
+
+	if status >= 400 {
+		httpErrorsTotal.WithLabelValues(route, statusClass(status)).Inc()
+	}
+}
+
+func statusClass(status int) string {
+	return strconv.Itoa(status/100) + "xx"
+}
@@ -0,0 +1,39 @@
+/*
+	Author: Christian (Sapphire) Godard
+	Date: 3/3/2025
+	File: middleware.go
+	Description: Gin middleware that assigns a request ID, records HTTP metrics, and attaches
+	             a correlated logger to the context for handlers to log through
+*/
+
+package main
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const requestIDHeader = "X-Request-Id"
+
+// RequestContextMiddleware assigns a request ID, times the request for recordHTTPRequest, and
+// attaches a logger carrying that request ID for downstream handlers to use
+func RequestContextMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(requestIDHeader)
+
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+
+		c.Writer.Header().Set(requestIDHeader, requestID)
+		c.Set(loggerContextKey, logger.With(zap.String("requestId", requestID)))
+
+		start := time.Now()
+		c.Next()
+
+		recordHTTPRequest(c.Request.Method, c.FullPath(), c.Writer.Status(), time.Since(start).Seconds())
+	}
+}
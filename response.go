@@ -0,0 +1,36 @@
+/*
+	Author: Christian (Sapphire) Godard
+	Date: 2/14/2025
+	File: response.go
+	Description: Shared response envelope so every handler returns JSON in the same shape,
+	             with numeric error codes callers can branch on instead of matching strings
+*/
+
+package main
+
+import "github.com/gin-gonic/gin"
+
+// Error codes returned in APIResponse.Code when ErrMsg is set
+const (
+	CodeOK                       = 0
+	CodeReceiptNotFound          = 1001
+	CodeValidationFailure        = 1002
+	CodePointsComputationFailure = 1003
+)
+
+// APIResponse is the envelope every /receipts endpoint responds with
+type APIResponse struct {
+	Code   int    `json:"code"`
+	ErrMsg string `json:"errMsg,omitempty"`
+	Data   any    `json:"data,omitempty"`
+}
+
+// RenderSuccess writes data wrapped in a successful APIResponse
+func RenderSuccess(c *gin.Context, status int, data any) {
+	c.IndentedJSON(status, APIResponse{Code: CodeOK, Data: data})
+}
+
+// RenderError writes an APIResponse carrying code and msg, and nothing in Data
+func RenderError(c *gin.Context, status int, code int, msg string) {
+	c.IndentedJSON(status, APIResponse{Code: code, ErrMsg: msg})
+}
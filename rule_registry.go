@@ -0,0 +1,106 @@
+/*
+	Author: Christian (Sapphire) Godard
+	Date: 2/21/2025
+	File: rule_registry.go
+	Description: Per-retailer overrides for the scoring engine, loaded from a YAML/JSON config
+	             file so operators can tweak rule constants or disable rules without recompiling
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RetailerRuleConfig overrides the default scoring constants for one retailer. Zero values
+// mean "use the default"; retailer is matched case-insensitively against Receipt.Retailer
+type RetailerRuleConfig struct {
+	Retailer              string   `json:"retailer" yaml:"retailer"`
+	RoundDollarPoints     int      `json:"roundDollarPoints,omitempty" yaml:"roundDollarPoints,omitempty"`
+	QuarterPoints         int      `json:"quarterPoints,omitempty" yaml:"quarterPoints,omitempty"`
+	ItemPairPoints        int      `json:"itemPairPoints,omitempty" yaml:"itemPairPoints,omitempty"`
+	DescriptionMultiplier float64  `json:"descriptionMultiplier,omitempty" yaml:"descriptionMultiplier,omitempty"`
+	OddDayPoints          int      `json:"oddDayPoints,omitempty" yaml:"oddDayPoints,omitempty"`
+	AfternoonPoints       int      `json:"afternoonPoints,omitempty" yaml:"afternoonPoints,omitempty"`
+	DisabledRules         []string `json:"disabledRules,omitempty" yaml:"disabledRules,omitempty"`
+}
+
+// RuleRegistry holds per-retailer overrides, keyed by lowercased retailer name
+type RuleRegistry struct {
+	overrides map[string]RetailerRuleConfig
+}
+
+// NewRuleRegistry builds an empty registry; every retailer falls back to defaultScorer
+func NewRuleRegistry() *RuleRegistry {
+	return &RuleRegistry{overrides: make(map[string]RetailerRuleConfig)}
+}
+
+// LoadRuleRegistry reads a list of RetailerRuleConfig from path, as YAML or JSON depending on
+// its extension
+func LoadRuleRegistry(path string) (*RuleRegistry, error) {
+	raw, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var configs []RetailerRuleConfig
+
+	if strings.HasSuffix(path, ".json") {
+		err = json.Unmarshal(raw, &configs)
+	} else {
+		err = yaml.Unmarshal(raw, &configs)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	registry := NewRuleRegistry()
+
+	for _, config := range configs {
+		registry.overrides[strings.ToLower(config.Retailer)] = config
+	}
+
+	return registry, nil
+}
+
+// ScorerFor returns the Scorer that should be used for retailer: defaultScorer unless an
+// override is registered for it
+func (registry *RuleRegistry) ScorerFor(retailer string) *Scorer {
+	config, exists := registry.overrides[strings.ToLower(retailer)]
+
+	if !exists {
+		return defaultScorer
+	}
+
+	disabled := make(map[string]bool, len(config.DisabledRules))
+
+	for _, name := range config.DisabledRules {
+		disabled[name] = true
+	}
+
+	candidates := []Rule{
+		AlphanumericRetailerRule{},
+		RoundDollarRule{Points: config.RoundDollarPoints},
+		QuarterMultipleRule{Points: config.QuarterPoints},
+		ItemsPairRule{PointsPerPair: config.ItemPairPoints},
+		DescriptionLengthRule{Multiplier: config.DescriptionMultiplier},
+		OddDayRule{Points: config.OddDayPoints},
+		AfternoonWindowRule{Points: config.AfternoonPoints},
+	}
+
+	rules := make([]Rule, 0, len(candidates))
+
+	for _, rule := range candidates {
+		if !disabled[ruleName(rule)] {
+			rules = append(rules, rule)
+		}
+	}
+
+	return NewScorer(rules...)
+}
@@ -0,0 +1,234 @@
+/*
+	Author: Christian (Sapphire) Godard
+	Date: 2/21/2025
+	File: scoring.go
+	Description: Scoring engine for receipts, broken into composable Rules so each point
+	             source is independently testable, explainable, and overridable per retailer
+*/
+
+package main
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rule scores one aspect of a receipt and explains why it awarded those points
+type Rule interface {
+	Apply(r Receipt) (points int, reason string)
+}
+
+// RuleResult is one rule's contribution to a receipt's total, as returned by Scorer.Score
+type RuleResult struct {
+	Rule   string `json:"rule"`
+	Points int    `json:"points"`
+	Reason string `json:"reason"`
+}
+
+// Scorer totals the points awarded by a fixed, ordered set of Rules
+type Scorer struct {
+	rules []Rule
+}
+
+// NewScorer builds a Scorer that applies rules in order
+func NewScorer(rules ...Rule) *Scorer {
+	return &Scorer{rules: rules}
+}
+
+// Score runs every rule against r and returns the total plus a per-rule breakdown
+func (s *Scorer) Score(r Receipt) (total int, breakdown []RuleResult) {
+	for _, rule := range s.rules {
+		points, reason := rule.Apply(r)
+
+		if points == 0 {
+			continue
+		}
+
+		total += points
+		breakdown = append(breakdown, RuleResult{Rule: ruleName(rule), Points: points, Reason: reason})
+	}
+
+	return total, breakdown
+}
+
+// AlphanumericRetailerRule awards a point for every alphanumeric character in the retailer name
+type AlphanumericRetailerRule struct{}
+
+func (AlphanumericRetailerRule) Apply(r Receipt) (int, string) {
+	points := 0
+
+	for _, char := range r.Retailer {
+		if (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || (char >= '0' && char <= '9') {
+			points++
+		}
+	}
+
+	return points, "one point per alphanumeric character in the retailer name"
+}
+
+// RoundDollarRule awards points when the total is a round dollar amount
+type RoundDollarRule struct {
+	Points int
+}
+
+func (rule RoundDollarRule) Apply(r Receipt) (int, string) {
+	total, _ := strconv.ParseFloat(r.Total, 64)
+
+	if math.Mod(total, 1.00) == 0 {
+		return rule.pointsOrDefault(), "total is a round dollar amount"
+	}
+
+	return 0, ""
+}
+
+func (rule RoundDollarRule) pointsOrDefault() int {
+	if rule.Points == 0 {
+		return 50
+	}
+
+	return rule.Points
+}
+
+// QuarterMultipleRule awards points when the total is a multiple of 0.25
+type QuarterMultipleRule struct {
+	Points int
+}
+
+func (rule QuarterMultipleRule) Apply(r Receipt) (int, string) {
+	total, _ := strconv.ParseFloat(r.Total, 64)
+
+	if math.Mod(total, 0.25) == 0 {
+		return rule.pointsOrDefault(), "total is a multiple of $0.25"
+	}
+
+	return 0, ""
+}
+
+func (rule QuarterMultipleRule) pointsOrDefault() int {
+	if rule.Points == 0 {
+		return 25
+	}
+
+	return rule.Points
+}
+
+// ItemsPairRule awards points for every two items on the receipt
+type ItemsPairRule struct {
+	PointsPerPair int
+}
+
+func (rule ItemsPairRule) Apply(r Receipt) (int, string) {
+	pairs := len(r.Items) / 2
+
+	if pairs == 0 {
+		return 0, ""
+	}
+
+	return pairs * rule.pointsPerPairOrDefault(), "points awarded per pair of items on the receipt"
+}
+
+func (rule ItemsPairRule) pointsPerPairOrDefault() int {
+	if rule.PointsPerPair == 0 {
+		return 5
+	}
+
+	return rule.PointsPerPair
+}
+
+// DescriptionLengthRule awards points on items whose trimmed description length is a multiple
+// of three, scaled by the item's price
+type DescriptionLengthRule struct {
+	Multiplier float64
+}
+
+func (rule DescriptionLengthRule) Apply(r Receipt) (int, string) {
+	points := 0
+
+	for _, item := range r.Items {
+		if len(strings.Trim(item.ShortDescription, " "))%3 != 0 {
+			continue
+		}
+
+		price, _ := strconv.ParseFloat(item.Price, 64)
+		points += int(math.Ceil(price * rule.multiplierOrDefault()))
+	}
+
+	if points == 0 {
+		return 0, ""
+	}
+
+	return points, "item description length is a multiple of 3"
+}
+
+func (rule DescriptionLengthRule) multiplierOrDefault() float64 {
+	if rule.Multiplier == 0 {
+		return 0.2
+	}
+
+	return rule.Multiplier
+}
+
+// OddDayRule awards points when the purchase date falls on an odd day of the month
+type OddDayRule struct {
+	Points int
+}
+
+func (rule OddDayRule) Apply(r Receipt) (int, string) {
+	date, err := time.Parse("2006-01-02", r.PurchaseDate)
+
+	if err != nil || date.Day()%2 == 0 {
+		return 0, ""
+	}
+
+	return rule.pointsOrDefault(), "purchase date is an odd day of the month"
+}
+
+func (rule OddDayRule) pointsOrDefault() int {
+	if rule.Points == 0 {
+		return 6
+	}
+
+	return rule.Points
+}
+
+// AfternoonWindowRule awards points when the purchase time falls between 2pm and 4pm
+type AfternoonWindowRule struct {
+	Points int
+}
+
+func (rule AfternoonWindowRule) Apply(r Receipt) (int, string) {
+	purchaseTime, err := time.Parse("15:04", r.PurchaseTime)
+
+	if err != nil || purchaseTime.Hour() < 14 || purchaseTime.Hour() >= 16 {
+		return 0, ""
+	}
+
+	return rule.pointsOrDefault(), "purchase time is between 2:00pm and 4:00pm"
+}
+
+func (rule AfternoonWindowRule) pointsOrDefault() int {
+	if rule.Points == 0 {
+		return 10
+	}
+
+	return rule.Points
+}
+
+// defaultScorer reproduces the API's original, unconfigurable scoring behavior
+var defaultScorer = NewScorer(
+	AlphanumericRetailerRule{},
+	RoundDollarRule{},
+	QuarterMultipleRule{},
+	ItemsPairRule{},
+	DescriptionLengthRule{},
+	OddDayRule{},
+	AfternoonWindowRule{},
+)
+
+// ruleName returns the bare type name of rule, e.g. "RoundDollarRule"
+func ruleName(rule Rule) string {
+	return reflect.TypeOf(rule).Name()
+}
@@ -0,0 +1,39 @@
+/*
+	Author: Christian (Sapphire) Godard
+	Date: 3/6/2025
+	File: sql_dialect.go
+	Description: Placeholder rewriting so the same query text can run against SQLite (which
+	             accepts positional "?" placeholders) and Postgres (which requires ordinal
+	             "$1, $2, ..." placeholders via lib/pq)
+*/
+
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+// rebindPlaceholders rewrites every "?" in query to "$1", "$2", ... when driverName is
+// "postgres". Every other driver's query text is returned unchanged
+func rebindPlaceholders(driverName string, query string) string {
+	if driverName != "postgres" {
+		return query
+	}
+
+	var rebound strings.Builder
+	placeholder := 0
+
+	for _, char := range query {
+		if char != '?' {
+			rebound.WriteRune(char)
+			continue
+		}
+
+		placeholder++
+		rebound.WriteByte('$')
+		rebound.WriteString(strconv.Itoa(placeholder))
+	}
+
+	return rebound.String()
+}
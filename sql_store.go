@@ -0,0 +1,296 @@
+/*
+	Author: Christian (Sapphire) Godard
+	Date: 2/11/2025
+	File: sql_store.go
+	Description: ReceiptStore backed by database/sql so receipts and their computed points
+	             survive restarts and can be queried later. Driver-agnostic: works against
+	             Postgres or SQLite depending on the DATABASE_URL supplied at startup
+*/
+
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+const receiptsSchema = `
+CREATE TABLE IF NOT EXISTS receipts (
+	id            TEXT PRIMARY KEY,
+	retailer      TEXT NOT NULL,
+	purchase_date TEXT NOT NULL,
+	purchase_time TEXT NOT NULL,
+	total         TEXT NOT NULL,
+	points        INTEGER NOT NULL,
+	status        TEXT NOT NULL DEFAULT ''
+);`
+
+const receiptItemsSchema = `
+CREATE TABLE IF NOT EXISTS receipt_items (
+	receipt_id        TEXT NOT NULL REFERENCES receipts(id),
+	position          INTEGER NOT NULL,
+	short_description TEXT NOT NULL,
+	price             TEXT NOT NULL,
+	PRIMARY KEY (receipt_id, position)
+);`
+
+// SQLStore is a ReceiptStore backed by a database/sql connection pool
+type SQLStore struct {
+	db         *sql.DB
+	driverName string
+}
+
+// NewSQLStore opens driverName/dataSourceName, validates the connection, and runs migrations
+func NewSQLStore(driverName string, dataSourceName string) (*SQLStore, error) {
+	db, err := sql.Open(driverName, dataSourceName)
+
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+
+	if driverName == "sqlite3" {
+		// SQLite serializes writers per connection; a pool would hand out separate
+		// (and, for ":memory:" DSNs, entirely distinct) connections otherwise
+		db.SetMaxOpenConns(1)
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("connecting to database: %w", err)
+	}
+
+	store := &SQLStore{db: db, driverName: driverName}
+
+	if err := store.migrate(); err != nil {
+		return nil, fmt.Errorf("running migrations: %w", err)
+	}
+
+	return store, nil
+}
+
+// rebind rewrites query's "?" placeholders for s.driverName
+func (s *SQLStore) rebind(query string) string {
+	return rebindPlaceholders(s.driverName, query)
+}
+
+func (s *SQLStore) migrate() error {
+	if _, err := s.db.Exec(receiptsSchema); err != nil {
+		return err
+	}
+
+	if _, err := s.db.Exec(receiptItemsSchema); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *SQLStore) Put(id string, r Receipt, points int) error {
+	tx, err := s.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		s.rebind(`INSERT INTO receipts (id, retailer, purchase_date, purchase_time, total, points)
+		 VALUES (?, ?, ?, ?, ?, ?)
+		 ON CONFLICT (id) DO UPDATE SET retailer = excluded.retailer, purchase_date = excluded.purchase_date,
+			purchase_time = excluded.purchase_time, total = excluded.total, points = excluded.points`),
+		id, r.Retailer, r.PurchaseDate, r.PurchaseTime, r.Total, points,
+	)
+	// status is intentionally left untouched here; it's only ever set via SetStatus
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, err := tx.Exec(s.rebind(`DELETE FROM receipt_items WHERE receipt_id = ?`), id); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for i, item := range r.Items {
+		if _, err := tx.Exec(
+			s.rebind(`INSERT INTO receipt_items (receipt_id, position, short_description, price) VALUES (?, ?, ?, ?)`),
+			id, i, item.ShortDescription, item.Price,
+		); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLStore) Get(id string) (StoredReceipt, error) {
+	row := s.db.QueryRow(
+		s.rebind(`SELECT id, retailer, purchase_date, purchase_time, total, points, status FROM receipts WHERE id = ?`), id,
+	)
+
+	receipt, err := scanReceipt(row)
+
+	if err == sql.ErrNoRows {
+		return StoredReceipt{}, ErrReceiptNotFound
+	}
+
+	if err != nil {
+		return StoredReceipt{}, err
+	}
+
+	receipt.Items, err = s.itemsFor(id)
+
+	if err != nil {
+		return StoredReceipt{}, err
+	}
+
+	return receipt, nil
+}
+
+func (s *SQLStore) itemsFor(receiptId string) ([]Item, error) {
+	rows, err := s.db.Query(
+		s.rebind(`SELECT short_description, price FROM receipt_items WHERE receipt_id = ? ORDER BY position`), receiptId,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]Item, 0)
+
+	for rows.Next() {
+		var item Item
+
+		if err := rows.Scan(&item.ShortDescription, &item.Price); err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+func (s *SQLStore) List(filter ReceiptFilter) ([]StoredReceipt, error) {
+	query := strings.Builder{}
+	query.WriteString(`SELECT id, retailer, purchase_date, purchase_time, total, points, status FROM receipts WHERE 1=1`)
+
+	args := make([]any, 0)
+
+	if filter.RetailerContains != "" {
+		query.WriteString(` AND retailer LIKE ?`)
+		args = append(args, "%"+filter.RetailerContains+"%")
+	}
+
+	if filter.PurchasedAfter != "" {
+		query.WriteString(` AND purchase_date >= ?`)
+		args = append(args, filter.PurchasedAfter)
+	}
+
+	if filter.PurchasedBefore != "" {
+		query.WriteString(` AND purchase_date <= ?`)
+		args = append(args, filter.PurchasedBefore)
+	}
+
+	if filter.MinPoints != 0 {
+		query.WriteString(` AND points >= ?`)
+		args = append(args, filter.MinPoints)
+	}
+
+	if filter.SortOrder == "asc" {
+		query.WriteString(` ORDER BY purchase_date ASC, purchase_time ASC`)
+	} else {
+		query.WriteString(` ORDER BY purchase_date DESC, purchase_time DESC`)
+	}
+
+	rows, err := s.db.Query(s.rebind(query.String()), args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Collected fully (and rows closed) before looking up each receipt's items below: itemsFor
+	// runs its own query, and a connection pool sized for SQLite can only serve one at a time
+	matches := make([]StoredReceipt, 0)
+
+	for rows.Next() {
+		receipt, err := scanReceipt(rows)
+
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		// total bounds aren't pushed into SQL since Total is stored as text; filter them here,
+		// before paginating, so a bound doesn't shrink an already-LIMIT-ed page
+		if filter.MinTotal != 0 && parseTotal(receipt.Total) < filter.MinTotal {
+			continue
+		}
+
+		if filter.MaxTotal != 0 && parseTotal(receipt.Total) > filter.MaxTotal {
+			continue
+		}
+
+		matches = append(matches, receipt)
+	}
+
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, err
+	}
+
+	rows.Close()
+
+	results := paginate(matches, filter.Page, filter.PageSize)
+
+	for i := range results {
+		items, err := s.itemsFor(results[i].ID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		results[i].Items = items
+	}
+
+	return results, nil
+}
+
+func (s *SQLStore) SetStatus(id string, status string) error {
+	result, err := s.db.Exec(s.rebind(`UPDATE receipts SET status = ? WHERE id = ?`), status, id)
+
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrReceiptNotFound
+	}
+
+	return nil
+}
+
+func (s *SQLStore) Close() error {
+	return s.db.Close()
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanReceipt(row rowScanner) (StoredReceipt, error) {
+	var receipt StoredReceipt
+
+	err := row.Scan(&receipt.ID, &receipt.Retailer, &receipt.PurchaseDate, &receipt.PurchaseTime, &receipt.Total, &receipt.Points, &receipt.Status)
+
+	return receipt, err
+}
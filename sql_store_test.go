@@ -0,0 +1,173 @@
+/*
+	Author: Christian (Sapphire) Godard
+	Date: 3/6/2025
+	File: sql_store_test.go
+	Description: Exercises SQLStore against an ephemeral in-memory SQLite database
+*/
+
+package main
+
+import "testing"
+
+func newTestSQLStore(t *testing.T) *SQLStore {
+	t.Helper()
+
+	store, err := NewSQLStore("sqlite3", ":memory:")
+
+	if err != nil {
+		t.Fatalf("NewSQLStore: %v", err)
+	}
+
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func testReceipt() Receipt {
+	return Receipt{
+		Retailer:     "Target",
+		PurchaseDate: "2025-01-02",
+		PurchaseTime: "13:01",
+		Total:        "35.00",
+		Items: []Item{
+			{ShortDescription: "Pepsi - 12-oz", Price: "3.50"},
+			{ShortDescription: "Doritos", Price: "4.25"},
+		},
+	}
+}
+
+func TestSQLStoreMigratesOnOpen(t *testing.T) {
+	store := newTestSQLStore(t)
+
+	if _, err := store.db.Exec(`SELECT 1 FROM receipts LIMIT 1`); err != nil {
+		t.Fatalf("receipts table missing after migrate: %v", err)
+	}
+
+	if _, err := store.db.Exec(`SELECT 1 FROM receipt_items LIMIT 1`); err != nil {
+		t.Fatalf("receipt_items table missing after migrate: %v", err)
+	}
+}
+
+func TestSQLStorePutGet(t *testing.T) {
+	store := newTestSQLStore(t)
+	receipt := testReceipt()
+
+	if err := store.Put("r1", receipt, 42); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	got, err := store.Get("r1")
+
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	if got.Points != 42 {
+		t.Errorf("Points = %d, want 42", got.Points)
+	}
+
+	if got.Retailer != receipt.Retailer {
+		t.Errorf("Retailer = %q, want %q", got.Retailer, receipt.Retailer)
+	}
+
+	if len(got.Items) != len(receipt.Items) {
+		t.Fatalf("Items = %d, want %d", len(got.Items), len(receipt.Items))
+	}
+
+	if got.Items[0].ShortDescription != receipt.Items[0].ShortDescription {
+		t.Errorf("Items[0].ShortDescription = %q, want %q", got.Items[0].ShortDescription, receipt.Items[0].ShortDescription)
+	}
+}
+
+func TestSQLStoreGetMissing(t *testing.T) {
+	store := newTestSQLStore(t)
+
+	if _, err := store.Get("missing"); err != ErrReceiptNotFound {
+		t.Errorf("Get(missing) err = %v, want ErrReceiptNotFound", err)
+	}
+}
+
+func TestSQLStoreList(t *testing.T) {
+	store := newTestSQLStore(t)
+
+	if err := store.Put("r1", testReceipt(), 10); err != nil {
+		t.Fatalf("Put r1: %v", err)
+	}
+
+	other := testReceipt()
+	other.Retailer = "Walmart"
+
+	if err := store.Put("r2", other, 20); err != nil {
+		t.Fatalf("Put r2: %v", err)
+	}
+
+	results, err := store.List(ReceiptFilter{})
+
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("List returned %d results, want 2", len(results))
+	}
+
+	filtered, err := store.List(ReceiptFilter{RetailerContains: "walmart"})
+
+	if err != nil {
+		t.Fatalf("List with filter: %v", err)
+	}
+
+	if len(filtered) != 1 || filtered[0].ID != "r2" {
+		t.Errorf("List(RetailerContains=walmart) = %+v, want only r2", filtered)
+	}
+}
+
+func TestSQLStoreListTotalBoundBeforePaging(t *testing.T) {
+	store := newTestSQLStore(t)
+
+	// Three receipts, sorted oldest-first by purchase date: r1 (10.00, excluded by MinTotal),
+	// r2 and r3 (20.00, matching). With page size 1, page 1 must be r2 and page 2 must be r3 —
+	// if MinTotal were applied after LIMIT/OFFSET, r1's exclusion would never "backfill" r3 in.
+	r1 := testReceipt()
+	r1.PurchaseDate = "2025-01-01"
+	r1.Total = "10.00"
+
+	r2 := testReceipt()
+	r2.PurchaseDate = "2025-01-02"
+	r2.Total = "20.00"
+
+	r3 := testReceipt()
+	r3.PurchaseDate = "2025-01-03"
+	r3.Total = "20.00"
+
+	if err := store.Put("r1", r1, 10); err != nil {
+		t.Fatalf("Put r1: %v", err)
+	}
+
+	if err := store.Put("r2", r2, 10); err != nil {
+		t.Fatalf("Put r2: %v", err)
+	}
+
+	if err := store.Put("r3", r3, 10); err != nil {
+		t.Fatalf("Put r3: %v", err)
+	}
+
+	page1, err := store.List(ReceiptFilter{MinTotal: 15, SortOrder: "asc", Page: 1, PageSize: 1})
+
+	if err != nil {
+		t.Fatalf("List page 1: %v", err)
+	}
+
+	if len(page1) != 1 || page1[0].ID != "r2" {
+		t.Errorf("page 1 = %+v, want only r2", page1)
+	}
+
+	page2, err := store.List(ReceiptFilter{MinTotal: 15, SortOrder: "asc", Page: 2, PageSize: 1})
+
+	if err != nil {
+		t.Fatalf("List page 2: %v", err)
+	}
+
+	if len(page2) != 1 || page2[0].ID != "r3" {
+		t.Errorf("page 2 = %+v, want only r3", page2)
+	}
+}
@@ -0,0 +1,56 @@
+/*
+	Author: Christian (Sapphire) Godard
+	Date: 2/10/2025
+	File: store.go
+	Description: Storage abstraction for receipts so the API can be backed by different
+	             persistence drivers (in-memory, SQL, ...) without the handlers caring which
+*/
+
+package main
+
+import "errors"
+
+// ErrReceiptNotFound is returned by a ReceiptStore when a lookup doesn't match any receipt
+var ErrReceiptNotFound = errors.New("receipt not found")
+
+// StoredReceipt is a Receipt plus the bookkeeping fields a store needs to persist alongside it
+type StoredReceipt struct {
+	ID     string `json:"id"`
+	Points int    `json:"points"`
+	Status string `json:"status,omitempty"`
+	Receipt
+}
+
+// ReceiptFilter narrows down the results returned by ReceiptStore.List
+type ReceiptFilter struct {
+	RetailerContains string
+	PurchasedAfter   string
+	PurchasedBefore  string
+	MinTotal         float64
+	MaxTotal         float64
+	MinPoints        int
+	Page             int
+	PageSize         int
+	// SortOrder is "asc" or "desc" (the default); it must be applied before Page/PageSize are
+	// used to slice the result, so the returned page is the correct one
+	SortOrder string
+}
+
+// ReceiptStore is implemented by every persistence driver the API can run on
+type ReceiptStore interface {
+	// Put saves (or overwrites) the receipt under id along with its computed points
+	Put(id string, r Receipt, points int) error
+
+	// Get returns the receipt stored under id, or ErrReceiptNotFound if there is none
+	Get(id string) (StoredReceipt, error)
+
+	// List returns the receipts matching filter, ordered and paged per filter.SortOrder,
+	// filter.Page, and filter.PageSize (newest first by default)
+	List(filter ReceiptFilter) ([]StoredReceipt, error)
+
+	// SetStatus attaches status (e.g. "processed", "flagged") to an existing receipt
+	SetStatus(id string, status string) error
+
+	// Close releases any resources (connections, file handles, ...) held by the store
+	Close() error
+}
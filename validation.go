@@ -0,0 +1,69 @@
+/*
+	Author: Christian (Sapphire) Godard
+	Date: 2/14/2025
+	File: validation.go
+	Description: Strict validation of incoming Receipts so malformed input is rejected with
+	             per-field detail instead of Gin's raw JSON binding error
+*/
+
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var (
+	retailerPattern = regexp.MustCompile(`^[\w\s\-&]+$`)
+	totalPattern    = regexp.MustCompile(`^\d+\.\d{2}$`)
+	pricePattern    = regexp.MustCompile(`^\d+\.\d{2}$`)
+)
+
+// FieldError describes one invalid field on a rejected request
+type FieldError struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+// ValidateReceipt checks every field of r and returns one FieldError per problem found.
+// A nil/empty result means r is well-formed
+func ValidateReceipt(r Receipt) []FieldError {
+	var errs []FieldError
+
+	if !retailerPattern.MatchString(r.Retailer) {
+		errs = append(errs, FieldError{"retailer", "must be non-empty and contain only letters, numbers, spaces, '-', or '&'"})
+	}
+
+	if !totalPattern.MatchString(r.Total) {
+		errs = append(errs, FieldError{"total", "must match ^\\d+\\.\\d{2}$"})
+	}
+
+	if _, err := time.Parse("2006-01-02", r.PurchaseDate); err != nil {
+		errs = append(errs, FieldError{"purchaseDate", "must be an ISO date (YYYY-MM-DD)"})
+	}
+
+	if _, err := time.Parse("15:04", r.PurchaseTime); err != nil {
+		errs = append(errs, FieldError{"purchaseTime", "must be a 24h time (HH:MM)"})
+	}
+
+	if len(r.Items) == 0 {
+		errs = append(errs, FieldError{"items", "must contain at least one item"})
+	}
+
+	for i, item := range r.Items {
+		if item.ShortDescription == "" {
+			errs = append(errs, FieldError{itemField(i, "shortDescription"), "must be non-empty"})
+		}
+
+		if !pricePattern.MatchString(item.Price) {
+			errs = append(errs, FieldError{itemField(i, "price"), "must match ^\\d+\\.\\d{2}$"})
+		}
+	}
+
+	return errs
+}
+
+func itemField(index int, field string) string {
+	return "items[" + strconv.Itoa(index) + "]." + field
+}
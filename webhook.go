@@ -0,0 +1,116 @@
+/*
+	Author: Christian (Sapphire) Godard
+	Date: 2/26/2025
+	File: webhook.go
+	Description: Delivers job-completion callbacks with retry/timeout semantics and an HMAC
+	             signature so recipients can verify a callback really came from this API
+*/
+
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// CallbackConfig controls how job-completion webhooks are delivered
+type CallbackConfig struct {
+	Timeout       time.Duration
+	RetryInterval time.Duration
+	MaxRetries    int
+}
+
+// callbackConfigFromEnv reads CALLBACK_TIMEOUT_SECONDS, CALLBACK_RETRY_INTERVAL_SECONDS, and
+// CALLBACK_MAX_RETRIES, falling back to sane defaults when unset
+func callbackConfigFromEnv() CallbackConfig {
+	return CallbackConfig{
+		Timeout:       time.Duration(intEnvOrDefault("CALLBACK_TIMEOUT_SECONDS", 5)) * time.Second,
+		RetryInterval: time.Duration(intEnvOrDefault("CALLBACK_RETRY_INTERVAL_SECONDS", 2)) * time.Second,
+		MaxRetries:    intEnvOrDefault("CALLBACK_MAX_RETRIES", 3),
+	}
+}
+
+func intEnvOrDefault(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+
+	if err != nil {
+		return fallback
+	}
+
+	return value
+}
+
+// webhookSecret signs callback payloads so recipients can verify authenticity via X-Signature
+func webhookSecret() string {
+	return os.Getenv("WEBHOOK_SECRET")
+}
+
+// sendCallback POSTs job's result to job.CallbackURL, retrying on failure per callbackConfigFromEnv
+func sendCallback(job Job) {
+	config := callbackConfigFromEnv()
+
+	payload, err := json.Marshal(map[string]any{"id": job.ReceiptID, "points": job.Points, "status": job.Status})
+
+	if err != nil {
+		logger.Error("Couldn't marshal callback payload", zap.String("jobId", job.ID), zap.Error(err))
+		return
+	}
+
+	client := &http.Client{Timeout: config.Timeout}
+
+	for attempt := 0; attempt <= config.MaxRetries; attempt++ {
+		job.Attempts = attempt + 1
+
+		if deliverCallback(client, job.CallbackURL, payload) {
+			job.CallbackStatus = CallbackDelivered
+			_ = jobStore.Put(job)
+			return
+		}
+
+		if attempt < config.MaxRetries {
+			time.Sleep(config.RetryInterval)
+		}
+	}
+
+	// CallbackStatus/CallbackError record delivery outcome separately from job.Status: a job that
+	// finished processing stays "completed" even if its callback could never be delivered
+	job.CallbackStatus = CallbackFailed
+	job.CallbackError = "callback delivery failed after " + strconv.Itoa(job.Attempts) + " attempt(s)"
+	_ = jobStore.Put(job)
+}
+
+func deliverCallback(client *http.Client, url string, payload []byte) bool {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+
+	if err != nil {
+		return false
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Signature", "sha256="+signPayload(payload))
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode >= 200 && resp.StatusCode < 300
+}
+
+// signPayload returns the hex-encoded HMAC-SHA256 of payload using webhookSecret
+func signPayload(payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(webhookSecret()))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
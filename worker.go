@@ -0,0 +1,81 @@
+/*
+	Author: Christian (Sapphire) Godard
+	Date: 2/26/2025
+	File: worker.go
+	Description: Background worker pool that computes points for async jobs and fires the
+	             requester's webhook callback once a job finishes
+*/
+
+package main
+
+import "go.uber.org/zap"
+
+// jobQueue carries the IDs of jobs waiting to be processed
+var jobQueue = make(chan string, 256)
+
+// startWorkerPool launches n workers pulling from jobQueue until it's closed
+func startWorkerPool(n int) {
+	for i := 0; i < n; i++ {
+		go worker()
+	}
+}
+
+// enqueuePendingJobs resumes any job left pending/processing by a previous run
+func enqueuePendingJobs() error {
+	pending, err := jobStore.PendingJobs()
+
+	if err != nil {
+		return err
+	}
+
+	for _, job := range pending {
+		jobQueue <- job.ID
+	}
+
+	return nil
+}
+
+func worker() {
+	for jobID := range jobQueue {
+		processJob(jobID)
+	}
+}
+
+// processJob scores the job's receipt, persists the result, and fires its callback if it has one
+func processJob(jobID string) {
+	job, err := jobStore.Get(jobID)
+
+	if err != nil {
+		logger.Error("Couldn't load job", zap.String("jobId", jobID), zap.Error(err))
+		return
+	}
+
+	job.Status = JobProcessing
+	_ = jobStore.Put(job)
+
+	receipt, err := store.Get(job.ReceiptID)
+
+	if err != nil {
+		job.Status = JobFailed
+		job.Error = err.Error()
+		_ = jobStore.Put(job)
+		return
+	}
+
+	points := receiptPoints(receipt.Receipt)
+
+	if err := store.Put(job.ReceiptID, receipt.Receipt, points); err != nil {
+		job.Status = JobFailed
+		job.Error = err.Error()
+		_ = jobStore.Put(job)
+		return
+	}
+
+	job.Status = JobCompleted
+	job.Points = points
+	_ = jobStore.Put(job)
+
+	if job.CallbackURL != "" {
+		sendCallback(job)
+	}
+}